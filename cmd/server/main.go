@@ -0,0 +1,193 @@
+// Command server is a thin HTTP wrapper around the verify package, kept
+// for operators who want a standalone verification service instead of
+// embedding the client in their own Go process.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/danikarik/go-twilio/verify"
+)
+
+// logSMSCallback is the default verify.SMSCallback for the bundled
+// server: it just logs delivery-status and inbound-message webhooks.
+// Operators embedding the verify package directly should supply their
+// own callback instead.
+type logSMSCallback struct{}
+
+func (logSMSCallback) HandleSMS(ctx context.Context, status *verify.SMSStatus) error {
+	log.Printf("sms webhook: sid=%s status=%s from=%s to=%s", status.MessageSID, status.Status, status.From, status.To)
+	return nil
+}
+
+// errorStatus maps a verify.PolicyError to its carried HTTP status and a
+// verify.TwilioError to the status Twilio reported. Any other error
+// (including a policy store returning an error, e.g. a quota/allow-list
+// backend being unreachable) is treated as a server error rather than a
+// client mistake.
+func errorStatus(err error) int {
+	switch e := err.(type) {
+	case *verify.PolicyError:
+		return e.StatusCode
+	case *verify.TwilioError:
+		if e.Status == 0 {
+			return http.StatusInternalServerError
+		}
+		return e.Status
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type envError struct{ Key string }
+
+func (e envError) Error() string { return fmt.Sprintf("[%s] is not present", e.Key) }
+
+func envLookup(keys ...string) (map[string]string, error) {
+	envs := make(map[string]string)
+	for _, key := range keys {
+		val, ok := os.LookupEnv(key)
+		if !ok {
+			return envs, envError{key}
+		}
+		envs[key] = val
+	}
+	return envs, nil
+}
+
+type server struct {
+	client  *verify.Client
+	webhook *verify.SMSWebhook
+}
+
+func (s *server) requestCode(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		To      string `json:"to"`
+		Channel string `json:"channel"`
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var payload request
+	if err := json.Unmarshal(data, &payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := s.client.CreateVerification(r.Context(), payload.To, payload.Channel)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *server) verifyCode(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		To   string `json:"to"`
+		Code string `json:"code"`
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var payload request
+	if err := json.Unmarshal(data, &payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := s.client.CheckVerification(r.Context(), payload.To, payload.Code)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if !response.Valid {
+		http.Error(w, "not valid", http.StatusNotAcceptable)
+		return
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *server) callCode(w http.ResponseWriter, r *http.Request) {
+	type request struct {
+		To   string `json:"to"`
+		Code string `json:"code"`
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var payload request
+	if err := json.Unmarshal(data, &payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := s.client.PlaceVerificationCall(r.Context(), payload.To, payload.Code)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/request", s.requestCode)
+	mux.HandleFunc("/verify", s.verifyCode)
+	mux.HandleFunc("/call", s.callCode)
+	mux.Handle("/webhook/sms", s.webhook)
+	mux.ServeHTTP(w, r)
+}
+
+func main() {
+	envs, err := envLookup(
+		"TWILIO_SERVICE_SID",
+		"TWILIO_ACCOUNT_SID",
+		"TWILIO_TOKEN",
+		"TWILIO_FROM_NUMBER",
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client := verify.NewClient(envs["TWILIO_SERVICE_SID"], envs["TWILIO_ACCOUNT_SID"], envs["TWILIO_TOKEN"])
+	client.FromNumber = envs["TWILIO_FROM_NUMBER"]
+
+	webhook := verify.NewSMSWebhook(envs["TWILIO_TOKEN"], logSMSCallback{})
+
+	srv := &http.Server{
+		Addr:         ":8080",
+		Handler:      &server{client: client, webhook: webhook},
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+	}
+
+	log.Println("Start listening on", srv.Addr)
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,328 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// fakeTransport is a BaseClient that returns a scripted response and
+// records the last request it saw, so tests can assert on method/URL/body
+// without touching the network.
+type fakeTransport struct {
+	do       func(r *http.Request) (*http.Response, error)
+	lastReq  *http.Request
+	lastBody string
+}
+
+func (f *fakeTransport) Do(r *http.Request) (*http.Response, error) {
+	f.lastReq = r
+	if r.Body != nil {
+		buf, _ := ioutil.ReadAll(r.Body)
+		f.lastBody = string(buf)
+	}
+	return f.do(r)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+	}
+}
+
+func newTestClient(transport *fakeTransport) *Client {
+	return &Client{
+		ServiceSID: "VAxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+		AccountSID: "ACxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+		AuthToken:  "test-auth-token",
+		FromNumber: "+15557654321",
+		HTTPClient: transport,
+	}
+}
+
+func TestCreateVerification(t *testing.T) {
+	transport := &fakeTransport{
+		do: func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusCreated, `{"sid":"VExxx","to":"+15551234567","channel":"sms","status":"pending"}`), nil
+		},
+	}
+	c := newTestClient(transport)
+
+	v, err := c.CreateVerification(context.Background(), "+15551234567", "sms")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.SID != "VExxx" || v.Status != "pending" {
+		t.Fatalf("unexpected verification: %+v", v)
+	}
+
+	if transport.lastReq.Method != http.MethodPost {
+		t.Fatalf("expected POST, got %s", transport.lastReq.Method)
+	}
+	if ct := transport.lastReq.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+		t.Fatalf("expected form content type, got %q", ct)
+	}
+	form, err := url.ParseQuery(transport.lastBody)
+	if err != nil {
+		t.Fatalf("could not parse request body: %v", err)
+	}
+	if form.Get("To") != "+15551234567" || form.Get("Channel") != "sms" {
+		t.Fatalf("unexpected form body: %v", form)
+	}
+}
+
+func TestCreateVerificationWithOptions(t *testing.T) {
+	transport := &fakeTransport{
+		do: func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusCreated, `{"sid":"VExxx"}`), nil
+		},
+	}
+	c := newTestClient(transport)
+
+	_, err := c.CreateVerification(context.Background(), "+15551234567", "sms",
+		WithLocale("es"),
+		WithCustomCode("123456"),
+		WithCustomMessage("Your code is {{code}}"),
+		WithRateLimits(map[string]string{"per-ip": "5"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	form, err := url.ParseQuery(transport.lastBody)
+	if err != nil {
+		t.Fatalf("could not parse request body: %v", err)
+	}
+	if form.Get("Locale") != "es" {
+		t.Fatalf("expected Locale=es, got %v", form)
+	}
+	if form.Get("CustomCode") != "123456" {
+		t.Fatalf("expected CustomCode=123456, got %v", form)
+	}
+	if form.Get("CustomMessage") != "Your code is {{code}}" {
+		t.Fatalf("expected CustomMessage, got %v", form)
+	}
+	if form.Get("RateLimits[per-ip]") != "5" {
+		t.Fatalf("expected bracketed RateLimits[per-ip]=5, got %v", form)
+	}
+}
+
+func TestCheckVerification(t *testing.T) {
+	transport := &fakeTransport{
+		do: func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusOK, `{"sid":"VExxx","valid":true,"status":"approved"}`), nil
+		},
+	}
+	c := newTestClient(transport)
+
+	v, err := c.CheckVerification(context.Background(), "+15551234567", "123456")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !v.Valid || v.Status != "approved" {
+		t.Fatalf("unexpected verification: %+v", v)
+	}
+}
+
+func TestCheckVerificationTwilioError(t *testing.T) {
+	transport := &fakeTransport{
+		do: func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusTooManyRequests, `{"code":20429,"message":"Too Many Requests","status":429}`), nil
+		},
+	}
+	c := newTestClient(transport)
+
+	_, err := c.CheckVerification(context.Background(), "+15551234567", "000000")
+	var twilioErr *TwilioError
+	if !errors.As(err, &twilioErr) {
+		t.Fatalf("expected *TwilioError, got %T: %v", err, err)
+	}
+	if twilioErr.Status != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, twilioErr.Status)
+	}
+}
+
+func TestDoRequestFallsBackToHTTPStatus(t *testing.T) {
+	// A proxy/WAF error page: valid JSON, but not Twilio's envelope, so
+	// TwilioError.Status decodes to the zero value. doRequest must not
+	// propagate that 0 - it should fall back to the real HTTP status.
+	transport := &fakeTransport{
+		do: func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusTooManyRequests, `{"error":"rate limited"}`), nil
+		},
+	}
+	c := newTestClient(transport)
+
+	_, err := c.CheckVerification(context.Background(), "+15551234567", "000000")
+	var twilioErr *TwilioError
+	if !errors.As(err, &twilioErr) {
+		t.Fatalf("expected *TwilioError, got %T: %v", err, err)
+	}
+	if twilioErr.Status != http.StatusTooManyRequests {
+		t.Fatalf("expected fallback status %d, got %d", http.StatusTooManyRequests, twilioErr.Status)
+	}
+}
+
+func TestDoRequestJSONBody(t *testing.T) {
+	type payload struct {
+		Foo string `json:"foo"`
+	}
+
+	transport := &fakeTransport{
+		do: func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusOK, `{}`), nil
+		},
+	}
+	c := newTestClient(transport)
+
+	u, err := c.methodURL("/Whatever")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := c.doRequest(context.Background(), http.MethodPost, u, payload{Foo: "bar"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ct := transport.lastReq.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json content type, got %q", ct)
+	}
+	if transport.lastBody != `{"foo":"bar"}` {
+		t.Fatalf("unexpected JSON body: %s", transport.lastBody)
+	}
+}
+
+func TestPlaceVerificationCall(t *testing.T) {
+	transport := &fakeTransport{
+		do: func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusCreated, `{"sid":"CAxxx","to":"+15551234567","from":"+15557654321","status":"queued"}`), nil
+		},
+	}
+	c := newTestClient(transport)
+
+	result, err := c.PlaceVerificationCall(context.Background(), "+15551234567", "123456")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.SID != "CAxxx" || result.Status != "queued" {
+		t.Fatalf("unexpected call result: %+v", result)
+	}
+
+	form, err := url.ParseQuery(transport.lastBody)
+	if err != nil {
+		t.Fatalf("could not parse request body: %v", err)
+	}
+	if form.Get("From") != "+15557654321" {
+		t.Fatalf("expected From to be the client's FromNumber, got %v", form)
+	}
+	if !strings.Contains(form.Get("Twiml"), "123456") {
+		t.Fatalf("expected the code in the TwiML payload, got %q", form.Get("Twiml"))
+	}
+}
+
+func TestGateRejectsInvalidPhoneNumber(t *testing.T) {
+	c := newTestClient(&fakeTransport{})
+	c.PhoneNumberConverter = func(ctx context.Context, raw string) (string, error) {
+		return "", errors.New("not a phone number")
+	}
+
+	_, err := c.gate(context.Background(), "not-a-number")
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected *PolicyError, got %T: %v", err, err)
+	}
+	if policyErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, policyErr.StatusCode)
+	}
+}
+
+type fakeVerifiedNumberStore struct{ allow bool }
+
+func (f fakeVerifiedNumberStore) IsVerified(ctx context.Context, e164 string) (bool, error) {
+	return f.allow, nil
+}
+
+func TestGateRejectsUnverifiedNumber(t *testing.T) {
+	c := newTestClient(&fakeTransport{})
+	c.VerifiedNumbers = fakeVerifiedNumberStore{allow: false}
+
+	_, err := c.gate(context.Background(), "+15551234567")
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected *PolicyError, got %T: %v", err, err)
+	}
+	if policyErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, policyErr.StatusCode)
+	}
+}
+
+type fakeQuotaStore struct{ allow bool }
+
+func (f fakeQuotaStore) Allow(ctx context.Context, e164 string) (bool, error) {
+	return f.allow, nil
+}
+
+func TestGateRejectsOverQuota(t *testing.T) {
+	c := newTestClient(&fakeTransport{})
+	c.VerifiedNumbers = fakeVerifiedNumberStore{allow: true}
+	c.Quota = fakeQuotaStore{allow: false}
+
+	_, err := c.gate(context.Background(), "+15551234567")
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected *PolicyError, got %T: %v", err, err)
+	}
+	if policyErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, policyErr.StatusCode)
+	}
+}
+
+func TestGateStoreFailureIsNotAPolicyError(t *testing.T) {
+	c := newTestClient(&fakeTransport{})
+	c.VerifiedNumbers = erroringVerifiedNumberStore{}
+
+	_, err := c.gate(context.Background(), "+15551234567")
+	var policyErr *PolicyError
+	if errors.As(err, &policyErr) {
+		t.Fatalf("expected a plain error for a store failure, got *PolicyError: %v", policyErr)
+	}
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+type erroringVerifiedNumberStore struct{}
+
+func (erroringVerifiedNumberStore) IsVerified(ctx context.Context, e164 string) (bool, error) {
+	return false, errors.New("store unreachable")
+}
+
+func TestGateAppliesConvertedNumber(t *testing.T) {
+	transport := &fakeTransport{
+		do: func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusCreated, `{"sid":"VExxx"}`), nil
+		},
+	}
+	c := newTestClient(transport)
+	c.PhoneNumberConverter = func(ctx context.Context, raw string) (string, error) {
+		return "+15551234567", nil
+	}
+
+	if _, err := c.CreateVerification(context.Background(), "(555) 123-4567", "sms"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	form, err := url.ParseQuery(transport.lastBody)
+	if err != nil {
+		t.Fatalf("could not parse request body: %v", err)
+	}
+	if form.Get("To") != "+15551234567" {
+		t.Fatalf("expected the converted E.164 number, got %v", form)
+	}
+}
@@ -0,0 +1,16 @@
+package verify
+
+import "fmt"
+
+// TwilioError is the error envelope Twilio returns on non-2xx responses,
+// e.g. {"code":60200,"message":"Invalid parameter","more_info":"https://www.twilio.com/docs/errors/60200","status":400}.
+type TwilioError struct {
+	Code     int    `json:"code"`
+	Message  string `json:"message"`
+	MoreInfo string `json:"more_info"`
+	Status   int    `json:"status"`
+}
+
+func (e *TwilioError) Error() string {
+	return fmt.Sprintf("twilio: %s (code %d, status %d): %s", e.Message, e.Code, e.Status, e.MoreInfo)
+}
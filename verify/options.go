@@ -0,0 +1,64 @@
+package verify
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// verificationRequest collects the parameters a CreateVerification call
+// can carry, built up from the required To/Channel plus any
+// VerificationOptions.
+type verificationRequest struct {
+	To            string
+	Channel       string
+	Locale        string
+	CustomCode    string
+	CustomMessage string
+	RateLimits    map[string]string
+}
+
+func (r *verificationRequest) values() url.Values {
+	v := url.Values{}
+	v.Set("To", r.To)
+	v.Set("Channel", r.Channel)
+	if r.Locale != "" {
+		v.Set("Locale", r.Locale)
+	}
+	if r.CustomCode != "" {
+		v.Set("CustomCode", r.CustomCode)
+	}
+	if r.CustomMessage != "" {
+		v.Set("CustomMessage", r.CustomMessage)
+	}
+	// Twilio expects each rate-limit bucket as its own bracketed form
+	// field (RateLimits[bucket]=value), not a single JSON blob.
+	for bucket, value := range r.RateLimits {
+		v.Set(fmt.Sprintf("RateLimits[%s]", bucket), value)
+	}
+	return v
+}
+
+// VerificationOption customizes a CreateVerification call.
+type VerificationOption func(*verificationRequest)
+
+// WithLocale sets the language Twilio uses for the SMS/voice message, e.g. "es".
+func WithLocale(locale string) VerificationOption {
+	return func(r *verificationRequest) { r.Locale = locale }
+}
+
+// WithCustomCode supplies the code to send instead of letting Twilio
+// generate one.
+func WithCustomCode(code string) VerificationOption {
+	return func(r *verificationRequest) { r.CustomCode = code }
+}
+
+// WithCustomMessage overrides the default verification message template.
+func WithCustomMessage(message string) VerificationOption {
+	return func(r *verificationRequest) { r.CustomMessage = message }
+}
+
+// WithRateLimits attaches custom rate-limit bucket values, keyed by the
+// rate limit's unique name as configured on the Verify service.
+func WithRateLimits(limits map[string]string) VerificationOption {
+	return func(r *verificationRequest) { r.RateLimits = limits }
+}
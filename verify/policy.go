@@ -0,0 +1,74 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// PhoneNumberConverter normalizes a raw user-supplied phone number (e.g.
+// "(415) 555-0100") into E.164 (e.g. "+14155550100") before it is sent to
+// Twilio. It also doubles as the first opportunity to reject input that
+// isn't a phone number at all.
+type PhoneNumberConverter func(ctx context.Context, rawInput string) (e164 string, err error)
+
+// VerifiedNumberStore restricts verification to a pre-registered
+// allow-list of numbers, e.g. so a tenant can only verify numbers its
+// users have already claimed.
+type VerifiedNumberStore interface {
+	IsVerified(ctx context.Context, e164 string) (bool, error)
+}
+
+// QuotaStore enforces a cap on how many verification requests a single
+// phone number may trigger, typically within a rolling window tracked by
+// the implementation.
+type QuotaStore interface {
+	Allow(ctx context.Context, e164 string) (bool, error)
+}
+
+// PolicyError is returned when a phone number fails normalization or one
+// of the configured policy gates, and carries the HTTP status callers
+// should surface to their own clients.
+type PolicyError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *PolicyError) Error() string { return e.Message }
+
+// gate runs to before Twilio is called, normalizing it with
+// PhoneNumberConverter (if set) and checking it against VerifiedNumbers
+// and Quota (if set), in that order. It returns the number to use in the
+// outbound request.
+func (c *Client) gate(ctx context.Context, to string) (string, error) {
+	e164 := to
+	if c.PhoneNumberConverter != nil {
+		converted, err := c.PhoneNumberConverter(ctx, to)
+		if err != nil {
+			return "", &PolicyError{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("invalid phone number: %s", err)}
+		}
+		e164 = converted
+	}
+
+	if c.VerifiedNumbers != nil {
+		ok, err := c.VerifiedNumbers.IsVerified(ctx, e164)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", &PolicyError{StatusCode: http.StatusForbidden, Message: "phone number is not on the verified allow-list"}
+		}
+	}
+
+	if c.Quota != nil {
+		ok, err := c.Quota.Allow(ctx, e164)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", &PolicyError{StatusCode: http.StatusTooManyRequests, Message: "verification quota exceeded for this phone number"}
+		}
+	}
+
+	return e164, nil
+}
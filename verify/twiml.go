@@ -0,0 +1,25 @@
+package verify
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// callTwiml renders the TwiML document read out over the phone call. The
+// code is spoken twice, separated by a short pause, so the recipient has
+// time to write it down on the first pass.
+var callTwiml = template.Must(template.New("call").Parse(
+	`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<Response><Say>Your verification code is {{.Code}}.</Say>` +
+		`<Pause length="2"/><Say>Again, your code is {{.Code}}.</Say></Response>`,
+))
+
+// twiml builds the TwiML payload for a verification call, escaping the
+// code so it cannot break out of the surrounding XML.
+func twiml(code string) (string, error) {
+	var buf bytes.Buffer
+	if err := callTwiml.Execute(&buf, struct{ Code string }{Code: template.HTMLEscapeString(code)}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,128 @@
+package verify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type recordingCallback struct {
+	status *SMSStatus
+}
+
+func (c *recordingCallback) HandleSMS(ctx context.Context, status *SMSStatus) error {
+	c.status = status
+	return nil
+}
+
+// sign reproduces Twilio's documented signature scheme for use in tests.
+func sign(authToken, fullURL string, form url.Values) string {
+	keys := make([]string, 0, len(form))
+	for k := range form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	buf.WriteString(fullURL)
+	for _, k := range keys {
+		buf.WriteString(k)
+		buf.WriteString(form.Get(k))
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(buf.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookRequest(fullURL string, form url.Values, signature string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, fullURL, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Twilio-Signature", signature)
+	// Tell the handler the request arrived over HTTPS the way a reverse
+	// proxy would, so the scheme comes from X-Forwarded-Proto.
+	r.Header.Set("X-Forwarded-Proto", "https")
+	return r
+}
+
+func TestSMSWebhookValidSignature(t *testing.T) {
+	const authToken = "test-auth-token"
+	const fullURL = "https://example.com/webhook/sms"
+
+	form := url.Values{}
+	form.Set("MessageSid", "SMxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx")
+	form.Set("MessageStatus", "delivered")
+	form.Set("From", "+15557654321")
+	form.Set("To", "+15551234567")
+
+	cb := &recordingCallback{}
+	wh := NewSMSWebhook(authToken, cb)
+
+	r := newWebhookRequest(fullURL, form, sign(authToken, fullURL, form))
+	w := httptest.NewRecorder()
+	wh.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if cb.status == nil || cb.status.MessageSID != "SMxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx" {
+		t.Fatalf("callback did not receive the expected status, got %+v", cb.status)
+	}
+}
+
+func TestSMSWebhookInvalidSignature(t *testing.T) {
+	const authToken = "test-auth-token"
+	const fullURL = "https://example.com/webhook/sms"
+
+	form := url.Values{}
+	form.Set("MessageSid", "SMxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx")
+	form.Set("MessageStatus", "delivered")
+
+	cb := &recordingCallback{}
+	wh := NewSMSWebhook(authToken, cb)
+
+	sig := sign(authToken, fullURL, form)
+
+	// Tamper with a field after signing, as an attacker forging the
+	// callback would.
+	tampered := url.Values{}
+	tampered.Set("MessageSid", form.Get("MessageSid"))
+	tampered.Set("MessageStatus", "failed")
+
+	r := newWebhookRequest(fullURL, tampered, sig)
+	w := httptest.NewRecorder()
+	wh.ServeHTTP(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d: %s", http.StatusForbidden, w.Code, w.Body.String())
+	}
+	if cb.status != nil {
+		t.Fatal("callback should not be invoked for an invalid signature")
+	}
+}
+
+func TestSMSWebhookMissingSignature(t *testing.T) {
+	const authToken = "test-auth-token"
+	const fullURL = "https://example.com/webhook/sms"
+
+	form := url.Values{}
+	form.Set("MessageSid", "SMxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx")
+
+	cb := &recordingCallback{}
+	wh := NewSMSWebhook(authToken, cb)
+
+	r := newWebhookRequest(fullURL, form, "")
+	w := httptest.NewRecorder()
+	wh.ServeHTTP(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d: %s", http.StatusForbidden, w.Code, w.Body.String())
+	}
+}